@@ -0,0 +1,147 @@
+package rhea
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+/******************************************************************************
+
+BEL export
+
+ToBEL and ReactionToBEL render parsed Rhea reactions as BEL (Biological
+Expression Language) reaction statements, of the form:
+
+	reaction(reactants(a(CHEBI:15377)), products(a(CHEBI:16761), a(CHEBI:15378)))
+
+so that Rhea reactions can be loaded into BEL-based pathway and network
+tooling without a separate conversion step. Each abundance term names its
+ChEBI reference directly off ReactivePart.Chebi, which holds an IRI such as
+"http://purl.obolibrary.org/obo/CHEBI_15377"; only the "CHEBI:15377" suffix
+is kept.
+
+A directional Reaction has one well-defined reactants/products statement,
+drawn from its Substrates and Products ReactionSide IRIs. A bidirectional
+Reaction has no fixed direction: it instead carries its two ReactionSides in
+SubstrateOrProducts, one entry per side (the same way Substrates and
+Products each hold one side for a directional reaction). The convention here
+is to emit both directions as two separate statements - SubstrateOrProducts
+entry 0 as reactants with entry 1 as products, and the reverse - each
+resolved to its own side's ChEBI references rather than merging the two
+sides together.
+
+******************************************************************************/
+
+// ToBEL writes one BEL reaction() statement per Reaction in rhea to w, one
+// statement per line. Bidirectional reactions produce two lines (forward and
+// reverse); see the package doc comment above for the convention used.
+func ToBEL(rhea Rhea, w io.Writer) error {
+	chebiByLink := chebiByCompoundLink(rhea)
+	participantsBySide := participantsByReactionSide(rhea)
+	for _, reaction := range rhea.Reactions {
+		if err := reactionToBEL(reaction, participantsBySide, chebiByLink, w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReactionToBEL writes reaction's BEL statement(s) to w. rhea must be the
+// Rhea value reaction was parsed from, since resolving a Reaction's
+// substrates and products to ChEBI references requires joining through
+// rhea.ReactionParticipants and rhea.ReactiveParts.
+func ReactionToBEL(reaction Reaction, rhea Rhea, w io.Writer) error {
+	return reactionToBEL(reaction, participantsByReactionSide(rhea), chebiByCompoundLink(rhea), w)
+}
+
+func reactionToBEL(reaction Reaction, participantsBySide map[string][]ReactionParticipant, chebiByLink map[string]string, w io.Writer) error {
+	if len(reaction.SubstrateOrProducts) > 0 {
+		sides := make([][]string, len(reaction.SubstrateOrProducts))
+		for i, side := range reaction.SubstrateOrProducts {
+			sides[i] = sideChebis(side, participantsBySide, chebiByLink)
+		}
+		for i := range sides {
+			for j := range sides {
+				if i == j {
+					continue
+				}
+				if err := writeBELStatement(sides[i], sides[j], w); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	reactants := sidesChebis(reaction.Substrates, participantsBySide, chebiByLink)
+	products := sidesChebis(reaction.Products, participantsBySide, chebiByLink)
+	return writeBELStatement(reactants, products, w)
+}
+
+func writeBELStatement(reactants, products []string, w io.Writer) error {
+	_, err := fmt.Fprintf(w, "reaction(reactants(%s), products(%s))\n", belAbundances(reactants), belAbundances(products))
+	return err
+}
+
+func belAbundances(chebis []string) string {
+	terms := make([]string, len(chebis))
+	for i, chebi := range chebis {
+		terms[i] = "a(" + chebi + ")"
+	}
+	return strings.Join(terms, ", ")
+}
+
+// sidesChebis resolves a list of ReactionSide IRIs that together make up one
+// side of a reaction (as found in Reaction.Substrates or Reaction.Products)
+// down to the CHEBI references of the ReactiveParts on them.
+func sidesChebis(reactionSides []string, participantsBySide map[string][]ReactionParticipant, chebiByLink map[string]string) []string {
+	var chebis []string
+	for _, side := range reactionSides {
+		chebis = append(chebis, sideChebis(side, participantsBySide, chebiByLink)...)
+	}
+	return chebis
+}
+
+// sideChebis resolves a single ReactionSide IRI down to the CHEBI references
+// of the ReactiveParts on it.
+func sideChebis(side string, participantsBySide map[string][]ReactionParticipant, chebiByLink map[string]string) []string {
+	var chebis []string
+	for _, participant := range participantsBySide[side] {
+		if chebi, ok := chebiByLink[participant.Compound]; ok && chebi != "" {
+			chebis = append(chebis, chebiToBELRef(chebi))
+		}
+	}
+	return chebis
+}
+
+// participantsByReactionSide indexes rhea.ReactionParticipants by
+// ReactionSide once, so that resolving every reaction's sides to ChEBI
+// references is O(reactions + participants) instead of O(reactions *
+// participants).
+func participantsByReactionSide(rhea Rhea) map[string][]ReactionParticipant {
+	bySide := make(map[string][]ReactionParticipant, len(rhea.ReactionParticipants))
+	for _, participant := range rhea.ReactionParticipants {
+		bySide[participant.ReactionSide] = append(bySide[participant.ReactionSide], participant)
+	}
+	return bySide
+}
+
+func chebiByCompoundLink(rhea Rhea) map[string]string {
+	chebiByLink := make(map[string]string, len(rhea.ReactiveParts))
+	for _, part := range rhea.ReactiveParts {
+		chebiByLink[part.CompoundReactionParticipantLink] = part.Chebi
+	}
+	return chebiByLink
+}
+
+// chebiToBELRef turns a ChEBI IRI such as
+// "http://purl.obolibrary.org/obo/CHEBI_15377" into a BEL namespace
+// reference "CHEBI:15377".
+func chebiToBELRef(chebiIRI string) string {
+	idx := strings.LastIndex(chebiIRI, "CHEBI_")
+	if idx == -1 {
+		return chebiIRI
+	}
+	return "CHEBI:" + chebiIRI[idx+len("CHEBI_"):]
+}
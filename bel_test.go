@@ -0,0 +1,72 @@
+package rhea
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReactionToBELBidirectional(t *testing.T) {
+	r := Rhea{
+		ReactiveParts: []ReactivePart{
+			{CompoundReactionParticipantLink: "compound-atp", Chebi: "http://purl.obolibrary.org/obo/CHEBI_15422"},
+			{CompoundReactionParticipantLink: "compound-adp", Chebi: "http://purl.obolibrary.org/obo/CHEBI_16761"},
+		},
+		ReactionParticipants: []ReactionParticipant{
+			{ReactionSide: "side-a", Compound: "compound-atp"},
+			{ReactionSide: "side-b", Compound: "compound-adp"},
+		},
+	}
+	reaction := Reaction{
+		Directional:         false,
+		SubstrateOrProducts: []string{"side-a", "side-b"},
+	}
+
+	var sb strings.Builder
+	if err := ReactionToBEL(reaction, r, &sb); err != nil {
+		t.Fatalf("ReactionToBEL returned error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(sb.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 BEL statements for a bidirectional reaction, got %d: %v", len(lines), lines)
+	}
+	if !strings.Contains(lines[0], "reactants(a(CHEBI:15422)), products(a(CHEBI:16761))") {
+		t.Errorf("forward statement missing expected sides, got %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "reactants(a(CHEBI:16761)), products(a(CHEBI:15422))") {
+		t.Errorf("reverse statement missing expected sides, got %q", lines[1])
+	}
+}
+
+func TestReactionToBELTransportBidirectional(t *testing.T) {
+	r := Rhea{
+		ReactiveParts: []ReactivePart{
+			{CompoundReactionParticipantLink: "compound-na-out", Chebi: "http://purl.obolibrary.org/obo/CHEBI_29101"},
+			{CompoundReactionParticipantLink: "compound-na-in", Chebi: "http://purl.obolibrary.org/obo/CHEBI_29101"},
+		},
+		ReactionParticipants: []ReactionParticipant{
+			{ReactionSide: "side-out", Compound: "compound-na-out"},
+			{ReactionSide: "side-in", Compound: "compound-na-in"},
+		},
+	}
+	reaction := Reaction{
+		Directional:         false,
+		IsTransport:         true,
+		SubstrateOrProducts: []string{"side-out", "side-in"},
+	}
+
+	var sb strings.Builder
+	if err := ReactionToBEL(reaction, r, &sb); err != nil {
+		t.Fatalf("ReactionToBEL returned error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(sb.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 BEL statements for a bidirectional transport reaction, got %d: %v", len(lines), lines)
+	}
+	for _, line := range lines {
+		if strings.Contains(line, "reactants()") || strings.Contains(line, "products()") {
+			t.Errorf("bidirectional transport reaction produced an empty side: %q", line)
+		}
+	}
+}
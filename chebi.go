@@ -0,0 +1,129 @@
+package rhea
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+/******************************************************************************
+
+ChEBI enrichment
+
+ReactivePart only records a ChEBI IRI (Chebi, e.g.
+"http://purl.obolibrary.org/obo/CHEBI_15377"). EnrichWithChEBI reads a ChEBI
+SDF dump (the format ChEBI ships as "ChEBI_complete.sdf") and joins it onto a
+parsed Rhea's ReactiveParts by ChEBI id, filling in InChI, InChIKey, and
+SMILES so downstream cheminformatics code (similarity search, dedup across
+databases) can work directly off of rhea.ReactiveParts without a second
+lookup against ChEBI.
+
+******************************************************************************/
+
+// chebiRecord is what EnrichWithChEBI needs out of one ChEBI SDF entry.
+type chebiRecord struct {
+	InChI    string
+	InChIKey string
+	SMILES   string
+}
+
+// EnrichWithChEBI parses the ChEBI SDF dump at chebiDumpPath and, for every
+// ReactivePart in rhea whose Chebi IRI matches an entry in the dump, fills in
+// InChI, InChIKey, and SMILES. ReactiveParts with no matching ChEBI entry are
+// left unchanged.
+func EnrichWithChEBI(rhea *Rhea, chebiDumpPath string) error {
+	f, err := os.Open(chebiDumpPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	records, err := parseChebiSDF(f)
+	if err != nil {
+		return err
+	}
+
+	for i, part := range rhea.ReactiveParts {
+		record, ok := records[chebiID(part.Chebi)]
+		if !ok {
+			continue
+		}
+		rhea.ReactiveParts[i].InChI = record.InChI
+		rhea.ReactiveParts[i].InChIKey = record.InChIKey
+		rhea.ReactiveParts[i].SMILES = record.SMILES
+	}
+	return nil
+}
+
+// parseChebiSDF reads an SDF file made up of "$$$$"-terminated entries, each
+// carrying a "> <ChEBI ID>" style data field per line of interest, and
+// returns the fields EnrichWithChEBI needs keyed by bare ChEBI id (e.g.
+// "15377", with no "CHEBI:" prefix).
+func parseChebiSDF(r *os.File) (map[string]chebiRecord, error) {
+	records := make(map[string]chebiRecord)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var id string
+	var current chebiRecord
+	var activeField string
+
+	flush := func() {
+		if id != "" {
+			records[id] = current
+		}
+		id, current, activeField = "", chebiRecord{}, ""
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "$$$$":
+			flush()
+		case strings.HasPrefix(line, "> <ChEBI ID>"):
+			activeField = "ChEBI ID"
+		case strings.HasPrefix(line, "> <InChI>"):
+			activeField = "InChI"
+		case strings.HasPrefix(line, "> <InChIKey>"):
+			activeField = "InChIKey"
+		case strings.HasPrefix(line, "> <SMILES>"):
+			activeField = "SMILES"
+		case strings.HasPrefix(line, ">"):
+			activeField = ""
+		case strings.TrimSpace(line) == "":
+			activeField = ""
+		case activeField != "":
+			switch activeField {
+			case "ChEBI ID":
+				id = chebiID(strings.TrimSpace(line))
+			case "InChI":
+				current.InChI = strings.TrimSpace(line)
+			case "InChIKey":
+				current.InChIKey = strings.TrimSpace(line)
+			case "SMILES":
+				current.SMILES = strings.TrimSpace(line)
+			}
+			activeField = ""
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+	return records, nil
+}
+
+// chebiID extracts the bare numeric ChEBI id from either an IRI
+// ("http://purl.obolibrary.org/obo/CHEBI_15377") or a CURIE
+// ("CHEBI:15377"), so that IRIs recorded on ReactivePart and ids read out of
+// an SDF dump can be joined on the same key.
+func chebiID(chebi string) string {
+	if idx := strings.LastIndex(chebi, "CHEBI_"); idx != -1 {
+		return chebi[idx+len("CHEBI_"):]
+	}
+	if idx := strings.LastIndex(chebi, "CHEBI:"); idx != -1 {
+		return chebi[idx+len("CHEBI:"):]
+	}
+	return chebi
+}
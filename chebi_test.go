@@ -0,0 +1,120 @@
+package rhea
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testChebiSDF = `CHEBI:15377
+  Marvin  01010000002D
+
+  0  0  0  0  0  0            999 V2000
+M  END
+> <ChEBI ID>
+CHEBI:15377
+
+> <InChI>
+InChI=1S/H2O/h1H2
+
+> <InChIKey>
+XLYOFNOQVPJJNP-UHFFFAOYSA-N
+
+> <SMILES>
+O
+
+$$$$
+CHEBI:15422
+  Marvin  01010000002D
+
+  0  0  0  0  0  0            999 V2000
+M  END
+> <ChEBI ID>
+CHEBI:15422
+
+> <InChI>
+InChI=1S/C10H16N5O13P3/c11-8-5-9(13-2-12-8)15(3-14-5)10-7(17)6(16)4(26-10)1-25-30(21,22)28-31(23,24)27-29(18,19)20/h2-4,6-7,10,16-17H,1H2,(H,21,22)(H,23,24)(H2,11,12,13)(H2,18,19,20)/t4-,6-,7-,10-/m1/s1
+
+> <InChIKey>
+ZKHQWZAMYRWXGA-KQYNXXCUSA-N
+
+> <SMILES>
+Nc1ncnc2n(cnc12)[C@@H]1O[C@H](COP(O)(=O)OP(O)(=O)OP(O)(O)=O)[C@@H](O)[C@H]1O
+
+$$$$
+`
+
+func writeTestSDF(t *testing.T) *os.File {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.sdf")
+	if err := os.WriteFile(path, []byte(testChebiSDF), 0o644); err != nil {
+		t.Fatalf("failed to write test SDF fixture: %v", err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open test SDF fixture: %v", err)
+	}
+	t.Cleanup(func() { f.Close() })
+	return f
+}
+
+func TestParseChebiSDF(t *testing.T) {
+	records, err := parseChebiSDF(writeTestSDF(t))
+	if err != nil {
+		t.Fatalf("parseChebiSDF returned error: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+
+	water, ok := records["15377"]
+	if !ok {
+		t.Fatalf("expected a record for ChEBI:15377")
+	}
+	if water.InChI != "InChI=1S/H2O/h1H2" {
+		t.Errorf("unexpected InChI for water: %q", water.InChI)
+	}
+	if water.InChIKey != "XLYOFNOQVPJJNP-UHFFFAOYSA-N" {
+		t.Errorf("unexpected InChIKey for water: %q", water.InChIKey)
+	}
+	if water.SMILES != "O" {
+		t.Errorf("unexpected SMILES for water: %q", water.SMILES)
+	}
+}
+
+func TestChebiID(t *testing.T) {
+	cases := map[string]string{
+		"http://purl.obolibrary.org/obo/CHEBI_15377": "15377",
+		"CHEBI:15377": "15377",
+		"15377":       "15377",
+	}
+	for input, want := range cases {
+		if got := chebiID(input); got != want {
+			t.Errorf("chebiID(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestEnrichWithChEBI(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.sdf")
+	if err := os.WriteFile(path, []byte(testChebiSDF), 0o644); err != nil {
+		t.Fatalf("failed to write test SDF fixture: %v", err)
+	}
+
+	r := &Rhea{
+		ReactiveParts: []ReactivePart{
+			{Chebi: "http://purl.obolibrary.org/obo/CHEBI_15377"},
+			{Chebi: "http://purl.obolibrary.org/obo/CHEBI_99999"},
+		},
+	}
+	if err := EnrichWithChEBI(r, path); err != nil {
+		t.Fatalf("EnrichWithChEBI returned error: %v", err)
+	}
+
+	if r.ReactiveParts[0].InChIKey != "XLYOFNOQVPJJNP-UHFFFAOYSA-N" {
+		t.Errorf("expected matching ReactivePart to be enriched, got %+v", r.ReactiveParts[0])
+	}
+	if r.ReactiveParts[1].InChIKey != "" {
+		t.Errorf("expected unmatched ReactivePart to be left alone, got %+v", r.ReactiveParts[1])
+	}
+}
@@ -0,0 +1,152 @@
+package rhea
+
+/******************************************************************************
+
+Index
+
+Index answers the questions users most often want out of Rhea - "which
+reactions involve this ChEBI?", "which reactions are annotated with this EC
+number?", "which reactions consume ATP and produce ADP?" - without making
+every caller hand-roll a linear scan over Rhea.Reactions. It is built once
+from a parsed Rhea value and holds map[string][]int indexes into
+Rhea.Reactions, keyed by the IRIs and EC numbers reactions are naturally
+looked up by.
+
+******************************************************************************/
+
+// Index is a set of lookups built from a Rhea value. The zero Index is not
+// usable; build one with NewIndex.
+type Index struct {
+	rhea Rhea
+
+	byChebi map[string][]int
+	byEC    map[string][]int
+
+	// sideChebis[i] holds, for Reaction i, one set per side: its substrates
+	// set and its products set (both empty for a bidirectional reaction),
+	// followed by one set per entry in SubstrateOrProducts (each entry is
+	// its own ReactionSide, not a combined side).
+	sideChebis [][]map[string]bool
+}
+
+// NewIndex builds an Index over rhea's Reactions. It resolves each
+// Reaction's substrates, products, and substratesOrProducts (ReactionSide
+// IRIs) down to the ChEBI references of the ReactiveParts on those sides,
+// via Rhea.ReactionParticipants.
+func NewIndex(rhea Rhea) *Index {
+	chebiByLink := chebiByCompoundLink(rhea)
+	chebisByReactionSide := make(map[string][]string)
+	for _, participant := range rhea.ReactionParticipants {
+		chebisByReactionSide[participant.ReactionSide] = append(chebisByReactionSide[participant.ReactionSide], chebiByLink[participant.Compound])
+	}
+
+	index := &Index{
+		rhea:       rhea,
+		byChebi:    make(map[string][]int),
+		byEC:       make(map[string][]int),
+		sideChebis: make([][]map[string]bool, len(rhea.Reactions)),
+	}
+
+	for i, reaction := range rhea.Reactions {
+		if reaction.Ec != "" {
+			index.byEC[reaction.Ec] = append(index.byEC[reaction.Ec], i)
+		}
+
+		sides := []map[string]bool{
+			chebiSet(reaction.Substrates, chebisByReactionSide),
+			chebiSet(reaction.Products, chebisByReactionSide),
+		}
+		for _, side := range reaction.SubstrateOrProducts {
+			sides = append(sides, chebiSetForSide(side, chebisByReactionSide))
+		}
+		index.sideChebis[i] = sides
+
+		seen := make(map[string]bool)
+		for _, side := range sides {
+			for chebi := range side {
+				if seen[chebi] {
+					continue
+				}
+				seen[chebi] = true
+				index.byChebi[chebi] = append(index.byChebi[chebi], i)
+			}
+		}
+	}
+	return index
+}
+
+// chebiSet resolves a list of ReactionSide IRIs that together make up one
+// side of a reaction (as in Reaction.Substrates or Reaction.Products) to the
+// set of ChEBI references found across them.
+func chebiSet(reactionSides []string, chebisByReactionSide map[string][]string) map[string]bool {
+	set := make(map[string]bool)
+	for _, side := range reactionSides {
+		for chebi := range chebiSetForSide(side, chebisByReactionSide) {
+			set[chebi] = true
+		}
+	}
+	return set
+}
+
+// chebiSetForSide resolves a single ReactionSide IRI to the set of ChEBI
+// references found on it.
+func chebiSetForSide(side string, chebisByReactionSide map[string][]string) map[string]bool {
+	set := make(map[string]bool)
+	for _, chebi := range chebisByReactionSide[side] {
+		if chebi != "" {
+			set[chebi] = true
+		}
+	}
+	return set
+}
+
+// ReactionsByChEBI returns every Reaction with a ReactivePart matching chebi
+// on any side.
+func (idx *Index) ReactionsByChEBI(chebi string) []Reaction {
+	return idx.reactionsAt(idx.byChebi[chebi])
+}
+
+// ReactionsByEC returns every Reaction annotated with EC number ec.
+func (idx *Index) ReactionsByEC(ec string) []Reaction {
+	return idx.reactionsAt(idx.byEC[ec])
+}
+
+// ReactionsInvolving returns every Reaction where all of chebis appear
+// together on the same side (mass-action style: ReactionsInvolving("ATP",
+// "ADP") finds reactions where ATP and ADP are both substrates, or both
+// products, or both members of substratesOrProducts - not reactions that
+// merely mention both somewhere).
+func (idx *Index) ReactionsInvolving(chebis ...string) []Reaction {
+	if len(chebis) == 0 {
+		return nil
+	}
+
+	candidates := idx.byChebi[chebis[0]]
+	var matches []int
+	for _, i := range candidates {
+		for _, side := range idx.sideChebis[i] {
+			if allPresent(side, chebis) {
+				matches = append(matches, i)
+				break
+			}
+		}
+	}
+	return idx.reactionsAt(matches)
+}
+
+func allPresent(set map[string]bool, chebis []string) bool {
+	for _, chebi := range chebis {
+		if !set[chebi] {
+			return false
+		}
+	}
+	return true
+}
+
+func (idx *Index) reactionsAt(indexes []int) []Reaction {
+	reactions := make([]Reaction, len(indexes))
+	for i, reactionIndex := range indexes {
+		reactions[i] = idx.rhea.Reactions[reactionIndex]
+	}
+	return reactions
+}
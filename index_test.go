@@ -0,0 +1,81 @@
+package rhea
+
+import "testing"
+
+func testIndexFixture() Rhea {
+	return Rhea{
+		ReactiveParts: []ReactivePart{
+			{CompoundReactionParticipantLink: "compound-atp", Chebi: "http://purl.obolibrary.org/obo/CHEBI_30616"},
+			{CompoundReactionParticipantLink: "compound-adp", Chebi: "http://purl.obolibrary.org/obo/CHEBI_456216"},
+			{CompoundReactionParticipantLink: "compound-water", Chebi: "http://purl.obolibrary.org/obo/CHEBI_15377"},
+			{CompoundReactionParticipantLink: "compound-phosphate", Chebi: "http://purl.obolibrary.org/obo/CHEBI_43474"},
+		},
+		ReactionParticipants: []ReactionParticipant{
+			{ReactionSide: "side-substrates", Compound: "compound-atp"},
+			{ReactionSide: "side-substrates", Compound: "compound-water"},
+			{ReactionSide: "side-products", Compound: "compound-adp"},
+			{ReactionSide: "side-products", Compound: "compound-phosphate"},
+			{ReactionSide: "side-a", Compound: "compound-atp"},
+			{ReactionSide: "side-b", Compound: "compound-adp"},
+		},
+		Reactions: []Reaction{
+			{
+				Id:         1,
+				Ec:         "ec-1",
+				Substrates: []string{"side-substrates"},
+				Products:   []string{"side-products"},
+			},
+			{
+				Id:                  2,
+				Directional:         false,
+				SubstrateOrProducts: []string{"side-a", "side-b"},
+			},
+		},
+	}
+}
+
+func TestReactionsByChEBI(t *testing.T) {
+	idx := NewIndex(testIndexFixture())
+
+	reactions := idx.ReactionsByChEBI("http://purl.obolibrary.org/obo/CHEBI_30616")
+	if len(reactions) != 2 {
+		t.Fatalf("expected ATP to appear in 2 reactions, got %d", len(reactions))
+	}
+}
+
+func TestReactionsByEC(t *testing.T) {
+	idx := NewIndex(testIndexFixture())
+
+	reactions := idx.ReactionsByEC("ec-1")
+	if len(reactions) != 1 || reactions[0].Id != 1 {
+		t.Fatalf("expected exactly reaction 1 for ec-1, got %+v", reactions)
+	}
+}
+
+func TestReactionsInvolvingSameSideDirectional(t *testing.T) {
+	idx := NewIndex(testIndexFixture())
+
+	reactions := idx.ReactionsInvolving(
+		"http://purl.obolibrary.org/obo/CHEBI_30616",
+		"http://purl.obolibrary.org/obo/CHEBI_15377",
+	)
+	if len(reactions) != 1 || reactions[0].Id != 1 {
+		t.Fatalf("expected ATP+water (both substrates of reaction 1) to match, got %+v", reactions)
+	}
+}
+
+func TestReactionsInvolvingDoesNotMatchOppositeSides(t *testing.T) {
+	idx := NewIndex(testIndexFixture())
+
+	// ATP and ADP are on opposite sides of both reactions: reaction 1 has
+	// ATP as a substrate and ADP as a product; reaction 2 (bidirectional)
+	// has ATP on side-a and ADP on side-b. Neither should match
+	// ReactionsInvolving, which requires co-occurrence on the *same* side.
+	reactions := idx.ReactionsInvolving(
+		"http://purl.obolibrary.org/obo/CHEBI_30616",
+		"http://purl.obolibrary.org/obo/CHEBI_456216",
+	)
+	if len(reactions) != 0 {
+		t.Fatalf("expected no reactions to match ATP+ADP on the same side, got %+v", reactions)
+	}
+}
@@ -1,6 +1,7 @@
 package rhea
 
 import (
+	"bytes"
 	"compress/gzip"
 	"encoding/xml"
 	"errors"
@@ -8,6 +9,8 @@ import (
 	"os"
 	"strconv"
 	"strings"
+
+	"github.com/Koeng101/rhea/rdf"
 )
 
 /******************************************************************************
@@ -242,7 +245,7 @@ type Rhea struct {
 type ReactivePart struct {
 	Id                              int    `json:"id" db:"id"`
 	Accession                       string `json:"accession" db:"accession"`
-	Position                        string `json:"position" db: "position"`
+	Position                        string `json:"position" db:"position"`
 	Name                            string `json:"name" db:"name"`
 	HtmlName                        string `json:"htmlName" db:"htmlname"`
 	Formula                         string `json:"formula" db:"formula"`
@@ -256,6 +259,11 @@ type ReactivePart struct {
 	CompoundName                    string `json:"name" db:"compoundname"`
 	CompoundHtmlName                string `json:"htmlName" db:"compoundhtmlname"`
 	CompoundType                    string `json:"compoundType" db:"compoundtype"`
+
+	// Populated by EnrichWithChEBI; empty until then.
+	InChI    string `json:"inchi,omitempty" db:"inchi"`
+	InChIKey string `json:"inchiKey,omitempty" db:"inchikey"`
+	SMILES   string `json:"smiles,omitempty" db:"smiles"`
 }
 
 type ReactionParticipant struct {
@@ -294,11 +302,142 @@ which contains all of the higher level structs
 
 ******************************************************************************/
 
+// rheaNS is the namespace every Rhea subclass URI (DirectionalReaction,
+// SmallMolecule, ...) is rooted under.
+const rheaNS = "http://rdf.rhea-db.org/"
+
+// reactionFields lists the predicates Project reads off a DirectionalReaction
+// or BidirectionalReaction subject to build a Reaction.
+var reactionFields = []string{
+	"id", "accession", "status", "comment", "equation", "htmlEquation",
+	"isChemicallyBalanced", "isTransport", "ec", "citation", "substrates",
+	"products", "substratesOrProducts", "location",
+}
+
+// reactivePartFields lists the predicates Project reads off a SmallMolecule
+// or Polymer subject to build a ReactivePart directly. subClassOf is
+// included so SubclassOfChebi can be read out of the same Record instead of
+// a second Walk.
+var reactivePartFields = []string{
+	"id", "accession", "position", "name", "htmlName", "formula", "charge",
+	"chebi", "underlyingChebi", "subClassOf",
+}
+
+// reactivePartSubclassFields lists the predicates Project reads off a
+// ReactivePart subject, which fills in the rest of a ReactivePart found
+// earlier via reactivePartMap (see ParseRhea).
+var reactivePartSubclassFields = []string{
+	"id", "accession", "position", "name", "htmlName", "formula", "charge", "chebi",
+}
+
+// genericReactivePartFields lists the predicates Project reads off a
+// GenericPolypeptide, GenericPolynucleotide, or GenericHeteropolysaccharide
+// subject, which only carries the Compound side of a ReactivePart - reactivePart
+// is included to resolve the ReactivePart Description it will be joined with
+// later.
+var genericReactivePartFields = []string{"id", "accession", "name", "htmlName", "reactivePart"}
+
+// reactionKinds maps each reaction subClassOf URI ParseRhea and StreamRhea
+// recognize to whether it is directional. It is the single place Rhea's
+// reaction vocabulary is recorded, so a future reaction subclass only needs
+// to be added here.
+var reactionKinds = []struct {
+	TypeURI     string
+	Directional bool
+}{
+	{rheaNS + "DirectionalReaction", true},
+	{rheaNS + "BidirectionalReaction", false},
+}
+
+// smallMoleculeTypes lists the subClassOf URIs, relative to rheaNS, whose
+// ReactivePart fields are read directly off the Compound Description itself
+// (see reactivePartFromRecord).
+var smallMoleculeTypes = []string{"SmallMolecule", "Polymer"}
+
+// genericCompoundTypes lists the subClassOf URIs, relative to rheaNS, that
+// only carry the Compound side of a ReactivePart; the rest is filled in
+// later from a separate Description whose subClassOf is reactivePartTypeURI
+// (see genericReactivePartFromRecord).
+var genericCompoundTypes = []string{"GenericPolypeptide", "GenericPolynucleotide", "GenericHeteropolysaccharide"}
+
+// reactivePartTypeURI is the subClassOf URI of the Description that fills in
+// the remaining ReactivePart fields for a compound found via
+// genericCompoundTypes.
+const reactivePartTypeURI = rheaNS + "ReactivePart"
+
+// reactionDirectional reports whether typeURI is a reaction subclass URI
+// known to reactionKinds, and if so whether it is directional.
+func reactionDirectional(typeURI string) (directional bool, ok bool) {
+	for _, kind := range reactionKinds {
+		if kind.TypeURI == typeURI {
+			return kind.Directional, true
+		}
+	}
+	return false, false
+}
+
+// isSmallMoleculeType reports whether typeURI is one of smallMoleculeTypes.
+func isSmallMoleculeType(typeURI string) bool {
+	for _, compoundType := range smallMoleculeTypes {
+		if typeURI == rheaNS+compoundType {
+			return true
+		}
+	}
+	return false
+}
+
+// isGenericCompoundType reports whether typeURI is one of
+// genericCompoundTypes.
+func isGenericCompoundType(typeURI string) bool {
+	for _, compoundType := range genericCompoundTypes {
+		if typeURI == rheaNS+compoundType {
+			return true
+		}
+	}
+	return false
+}
+
+// subclassOfChebi scans a Description's subClassOf values for a CHEBI URI,
+// used to fill ReactivePart.SubclassOfChebi. Shared by ParseRhea and
+// StreamRhea so a compound with more than one CHEBI subclass is resolved the
+// same way in both.
+func subclassOfChebi(subclasses []string) string {
+	var out string
+	for _, sc := range subclasses {
+		if strings.Contains(sc, "CHEBI") {
+			out = sc
+		}
+	}
+	return out
+}
+
+// participantFromContains builds the ReactionParticipant that a contains*
+// predicate (contains1, containsN, contains2n, containsNminus1,
+// containsNplus1, ...) on reactionSide describes, resolving object (the
+// Compound IRI the predicate points at) to its ChemComp IRI via compoundMap.
+// ParseRhea and StreamRhea share this so Rhea's contains* vocabulary only
+// needs to be handled correctly in one place.
+func participantFromContains(reactionSide, predicate, object string, compoundMap map[string]string) (ReactionParticipant, error) {
+	switch predicate {
+	case "containsN":
+		return ReactionParticipant{ReactionSide: reactionSide, Contains: 1, ContainsN: true, Compound: compoundMap[object]}, nil
+	case "contains2n":
+		return ReactionParticipant{ReactionSide: reactionSide, Contains: 2, ContainsN: true, Compound: compoundMap[object]}, nil
+	case "containsNminus1":
+		return ReactionParticipant{ReactionSide: reactionSide, Contains: 1, ContainsN: true, Minus: true, Compound: compoundMap[object]}, nil
+	case "containsNplus1":
+		return ReactionParticipant{ReactionSide: reactionSide, Contains: 1, ContainsN: true, Plus: true, Compound: compoundMap[object]}, nil
+	default:
+		i, err := strconv.Atoi(predicate[8:])
+		if err != nil {
+			return ReactionParticipant{}, err
+		}
+		return ReactionParticipant{ReactionSide: reactionSide, Contains: i, Compound: compoundMap[object]}, nil
+	}
+}
+
 func ParseRhea(rheaBytes []byte) (Rhea, error) {
-	var err error
-	// Read rheaBytes into a RheaRdf object
-	var rdf RheaRdf
-	err = xml.Unmarshal(rheaBytes, &rdf)
+	graph, err := rdf.Parse(bytes.NewReader(rheaBytes))
 	if err != nil {
 		return Rhea{}, err
 	}
@@ -308,180 +447,183 @@ func ParseRhea(rheaBytes []byte) (Rhea, error) {
 	compoundMap := make(map[string]string)
 	reactivePartMap := make(map[string]ReactivePart)
 
-	for _, description := range rdf.Descriptions {
+	// Every Description can link a Compound to its ReactivePart/ChemComp,
+	// regardless of its subclass - build that map in one pass up front.
+	for _, subject := range graph.Subjects() {
+		subclasses := graph.Walk(subject, "subClassOf")
+		reactivePartResource := first(graph.Walk(subject, "reactivePart"))
+		compoundResource := first(graph.Walk(subject, "compound"))
+
 		// Handle the case of a single compound -> reactive part, such as
 		// <rdf:Description rdf:about="http://rdf.rhea-db.org/Compound_10594">
 		// 	<rh:reactivePart rdf:resource="http://rdf.rhea-db.org/Compound_10594_rp2"/>
 		// </rdf:Description>
-		if (len(description.Subclass) == 0) && (description.ReactivePartXml.Resource != "") {
-			compoundMap[description.ReactivePartXml.Resource] = description.About
+		if len(subclasses) == 0 && reactivePartResource != "" {
+			compoundMap[reactivePartResource] = subject
 		}
-		if description.Compound.Resource != "" {
-			compoundMap[description.About] = description.Compound.Resource
+		if compoundResource != "" {
+			compoundMap[subject] = compoundResource
 		}
+	}
 
-		for _, subclass := range description.Subclass {
-			switch subclass.Resource {
-			case "http://rdf.rhea-db.org/DirectionalReaction":
-				newReaction := Reaction{
-					Id:                   description.Id,
-					Directional:          true,
-					Accession:            description.Accession,
-					Status:               description.Status.Resource,
-					Comment:              description.Comment,
-					Equation:             description.Equation,
-					HtmlEquation:         description.HtmlEquation,
-					IsChemicallyBalanced: description.IsChemicallyBalanced,
-					IsTransport:          description.IsTransport,
-					Ec:                   description.EC.Resource,
-					Citations:            description.CitationStrings(),
-					Substrates:           description.SubstrateStrings(),
-					Products:             description.ProductStrings(),
-					SubstrateOrProducts:  description.SubstrateOrProductStrings(),
-					Location:             description.Location.Resource}
-				rhea.Reactions = append(rhea.Reactions, newReaction)
-			case "http://rdf.rhea-db.org/BidirectionalReaction":
-				newReaction := Reaction{
-					Id:                   description.Id,
-					Directional:          false,
-					Accession:            description.Accession,
-					Status:               description.Status.Resource,
-					Comment:              description.Comment,
-					Equation:             description.Equation,
-					HtmlEquation:         description.HtmlEquation,
-					IsChemicallyBalanced: description.IsChemicallyBalanced,
-					IsTransport:          description.IsTransport,
-					Ec:                   description.EC.Resource,
-					Citations:            description.CitationStrings(),
-					Substrates:           description.SubstrateStrings(),
-					Products:             description.ProductStrings(),
-					SubstrateOrProducts:  description.SubstrateOrProductStrings(),
-					Location:             description.Location.Resource}
-				rhea.Reactions = append(rhea.Reactions, newReaction)
-			case "http://rdf.rhea-db.org/SmallMolecule", "http://rdf.rhea-db.org/Polymer":
-				compoundType := subclass.Resource[23:]
-				newReactivePart := ReactivePart{
-					Id:        description.Id,
-					Accession: description.Accession,
-					Position:  description.Position,
-					Name:      description.Name,
-					HtmlName:  description.HtmlName,
-					Formula:   description.Formula,
-					Charge:    description.Charge,
-					Chebi:     description.Chebi.Resource,
-
-					CompoundReactionParticipantLink: description.About,
-					CompoundId:                      description.Id,
-					CompoundAccession:               description.Accession,
-					CompoundName:                    description.Name,
-					CompoundHtmlName:                description.HtmlName,
-					CompoundType:                    compoundType}
-				if compoundType == "Polymer" {
-					newReactivePart.Chebi = description.UnderlyingChebi.Resource
-				}
-				// Add subclass Chebi
-				for _, sc := range description.Subclass {
-					if strings.Contains(sc.Resource, "CHEBI") {
-						newReactivePart.SubclassOfChebi = sc.Resource
-					}
-				}
-				// Add new reactive parts and new compounds to rhea
-				rhea.ReactiveParts = append(rhea.ReactiveParts, newReactivePart)
-			case "http://rdf.rhea-db.org/GenericPolypeptide", "http://rdf.rhea-db.org/GenericPolynucleotide", "http://rdf.rhea-db.org/GenericHeteropolysaccharide":
-				compoundType := subclass.Resource[23:]
-				newReactivePart := ReactivePart{
-					CompoundId:        description.Id,
-					CompoundAccession: description.Accession,
-					CompoundName:      description.Name,
-					CompoundHtmlName:  description.HtmlName,
-					CompoundType:      compoundType}
-				reactivePartMap[description.About] = newReactivePart
-				compoundMap[description.ReactivePartXml.Resource] = description.About
-			}
+	// Reactions and ReactiveParts are then read declaratively: each Rhea
+	// subclass URI maps to a Schema describing the predicates that kind of
+	// subject carries, and Project resolves every subject of that kind in
+	// one pass instead of a hardcoded per-subject switch.
+	for _, kind := range reactionKinds {
+		for _, record := range graph.Project(rdf.Schema{TypeURI: kind.TypeURI, Fields: reactionFields}) {
+			rhea.Reactions = append(rhea.Reactions, reactionFromRecord(record, kind.Directional))
+		}
+	}
+	for _, compoundType := range smallMoleculeTypes {
+		for _, record := range graph.Project(rdf.Schema{TypeURI: rheaNS + compoundType, Fields: reactivePartFields}) {
+			rhea.ReactiveParts = append(rhea.ReactiveParts, reactivePartFromRecord(record, compoundType))
+		}
+	}
+	for _, compoundType := range genericCompoundTypes {
+		for _, record := range graph.Project(rdf.Schema{TypeURI: rheaNS + compoundType, Fields: genericReactivePartFields}) {
+			reactivePartMap[record.Subject] = genericReactivePartFromRecord(record, compoundType)
+			compoundMap[first(record.Values["reactivePart"])] = record.Subject
 		}
 	}
 
-	// Go back and get the ReactiveParts
-	for _, description := range rdf.Descriptions {
-		for _, containsx := range description.ContainsX {
-			if strings.Contains(containsx.XMLName.Local, "contains") {
-				// Get reaction sides
-				// gzip -d -k -c rhea.rdf.gz | grep -o -P '(?<=contains).*(?= rdf)' | tr ' ' '\n' | sort -u | tr '\n' ' '
-				// The exceptions to numeric contains are 2n, N, Nminus1, and Nplus1
-				var newReactionParticipant ReactionParticipant
-				switch containsx.XMLName.Local {
-				case "containsN":
-					newReactionParticipant = ReactionParticipant{
-						ReactionSide: description.About,
-						Contains:     1,
-						ContainsN:    true,
-						Minus:        false,
-						Plus:         false,
-						Compound:     compoundMap[containsx.Content]}
-				case "contains2n":
-					newReactionParticipant = ReactionParticipant{
-						ReactionSide: description.About,
-						Contains:     2,
-						ContainsN:    true,
-						Minus:        false,
-						Plus:         false,
-						Compound:     compoundMap[containsx.Content]}
-				case "containsNminus1":
-					newReactionParticipant = ReactionParticipant{
-						ReactionSide: description.About,
-						Contains:     1,
-						ContainsN:    true,
-						Minus:        true,
-						Plus:         false,
-						Compound:     compoundMap[containsx.Content]}
-				case "containsNplus1":
-					newReactionParticipant = ReactionParticipant{
-						ReactionSide: description.About,
-						Contains:     1,
-						ContainsN:    true,
-						Minus:        false,
-						Plus:         true,
-						Compound:     compoundMap[containsx.Content]}
-				default:
-					i, err := strconv.Atoi(containsx.XMLName.Local[8:])
-					if err != nil {
-						return Rhea{}, err
-					}
-					newReactionParticipant = ReactionParticipant{
-						ReactionSide: description.About,
-						Contains:     i,
-						ContainsN:    false,
-						Minus:        false,
-						Plus:         false,
-						Compound:     compoundMap[containsx.Content]}
-				}
-				rhea.ReactionParticipants = append(rhea.ReactionParticipants, newReactionParticipant)
+	// Go back and get the ReactionParticipants and the remaining
+	// ReactiveParts (those split across a Compound Description and a
+	// separate ReactivePart Description, joined above via compoundMap).
+	for _, subject := range graph.Subjects() {
+		for _, triple := range graph.ForSubject(subject) {
+			// Get reaction sides
+			// gzip -d -k -c rhea.rdf.gz | grep -o -P '(?<=contains).*(?= rdf)' | tr ' ' '\n' | sort -u | tr '\n' ' '
+			// The exceptions to numeric contains are 2n, N, Nminus1, and Nplus1
+			if triple.Predicate == "contains" || !strings.Contains(triple.Predicate, "contains") {
+				continue
 			}
+			newReactionParticipant, err := participantFromContains(subject, triple.Predicate, triple.Object, compoundMap)
+			if err != nil {
+				return Rhea{}, err
+			}
+			rhea.ReactionParticipants = append(rhea.ReactionParticipants, newReactionParticipant)
 		}
+	}
 
-		for _, subclass := range description.Subclass {
-			switch subclass.Resource {
-			case "http://rdf.rhea-db.org/ReactivePart":
-				newReactivePart, ok := reactivePartMap[compoundMap[description.About]]
-				if ok != true {
-					return Rhea{}, errors.New("Could not find " + description.About)
-				}
-				newReactivePart.CompoundReactionParticipantLink = description.About
-				newReactivePart.Id = description.Id
-				newReactivePart.Accession = description.Accession
-				newReactivePart.Position = description.Position
-				newReactivePart.Name = description.Name
-				newReactivePart.HtmlName = description.HtmlName
-				newReactivePart.Formula = description.Formula
-				newReactivePart.Charge = description.Charge
-				newReactivePart.Chebi = description.Chebi.Resource
-				rhea.ReactiveParts = append(rhea.ReactiveParts, newReactivePart)
-			}
+	for _, record := range graph.Project(rdf.Schema{TypeURI: reactivePartTypeURI, Fields: reactivePartSubclassFields}) {
+		newReactivePart, ok := reactivePartMap[compoundMap[record.Subject]]
+		if ok != true {
+			return Rhea{}, errors.New("Could not find " + record.Subject)
 		}
+		v := record.Values
+		newReactivePart.CompoundReactionParticipantLink = record.Subject
+		newReactivePart.Id = atoiOrZero(first(v["id"]))
+		newReactivePart.Accession = first(v["accession"])
+		newReactivePart.Position = first(v["position"])
+		newReactivePart.Name = first(v["name"])
+		newReactivePart.HtmlName = first(v["htmlName"])
+		newReactivePart.Formula = first(v["formula"])
+		newReactivePart.Charge = first(v["charge"])
+		newReactivePart.Chebi = first(v["chebi"])
+		rhea.ReactiveParts = append(rhea.ReactiveParts, newReactivePart)
 	}
 	return rhea, nil
 }
 
+// reactionFromRecord builds a Reaction from a Record projected through
+// reactionFields. directional distinguishes DirectionalReaction (true) from
+// BidirectionalReaction (false); everything else is read the same way
+// regardless of directionality.
+func reactionFromRecord(record rdf.Record, directional bool) Reaction {
+	v := record.Values
+	return Reaction{
+		Id:                   atoiOrZero(first(v["id"])),
+		Directional:          directional,
+		Accession:            first(v["accession"]),
+		Status:               first(v["status"]),
+		Comment:              first(v["comment"]),
+		Equation:             first(v["equation"]),
+		HtmlEquation:         first(v["htmlEquation"]),
+		IsChemicallyBalanced: parseBool(first(v["isChemicallyBalanced"])),
+		IsTransport:          parseBool(first(v["isTransport"])),
+		Ec:                   first(v["ec"]),
+		Citations:            v["citation"],
+		Substrates:           v["substrates"],
+		Products:             v["products"],
+		SubstrateOrProducts:  v["substratesOrProducts"],
+		Location:             first(v["location"])}
+}
+
+// reactivePartFromRecord builds the SmallMolecule/Polymer shape of a
+// ReactivePart from a Record projected through reactivePartFields (see
+// genericReactivePartFromRecord for the other shape, which has fewer fields
+// available at this point and is filled in later via the ReactivePart
+// subclass).
+func reactivePartFromRecord(record rdf.Record, compoundType string) ReactivePart {
+	v := record.Values
+	id := atoiOrZero(first(v["id"]))
+	accession := first(v["accession"])
+	name := first(v["name"])
+	htmlName := first(v["htmlName"])
+	chebi := first(v["chebi"])
+	if compoundType == "Polymer" {
+		chebi = first(v["underlyingChebi"])
+	}
+	return ReactivePart{
+		Id:              id,
+		Accession:       accession,
+		Position:        first(v["position"]),
+		Name:            name,
+		HtmlName:        htmlName,
+		Formula:         first(v["formula"]),
+		Charge:          first(v["charge"]),
+		Chebi:           chebi,
+		SubclassOfChebi: subclassOfChebi(v["subClassOf"]),
+
+		CompoundReactionParticipantLink: record.Subject,
+		CompoundId:                      id,
+		CompoundAccession:               accession,
+		CompoundName:                    name,
+		CompoundHtmlName:                htmlName,
+		CompoundType:                    compoundType}
+}
+
+// genericReactivePartFromRecord builds the GenericPolypeptide/
+// GenericPolynucleotide/GenericHeteropolysaccharide shape of a ReactivePart
+// from a Record projected through genericReactivePartFields.
+func genericReactivePartFromRecord(record rdf.Record, compoundType string) ReactivePart {
+	v := record.Values
+	return ReactivePart{
+		CompoundId:        atoiOrZero(first(v["id"])),
+		CompoundAccession: first(v["accession"]),
+		CompoundName:      first(v["name"]),
+		CompoundHtmlName:  first(v["htmlName"]),
+		CompoundType:      compoundType}
+}
+
+// first returns values[0], or "" if values is empty - used to read a
+// single-valued predicate (e.g. rh:accession) off a Graph walk, which always
+// returns a slice.
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// atoiOrZero parses s as an int, returning 0 if s is empty or not a valid
+// integer (e.g. because the predicate it came from wasn't present).
+func atoiOrZero(s string) int {
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return 0
+	}
+	return i
+}
+
+// parseBool parses s as a bool, returning false if s is empty or not a
+// valid bool (e.g. because the predicate it came from wasn't present).
+func parseBool(s string) bool {
+	b, _ := strconv.ParseBool(s)
+	return b
+}
+
 func ReadRhea(gzipPath string) ([]byte, error) {
 	// Get gz'd file bytes
 	xmlFile, err := os.Open("data/rhea.rdf.gz")
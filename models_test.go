@@ -28,3 +28,111 @@ func TestParse(t *testing.T) {
 		fmt.Println(c.CompoundReactionParticipantLink)
 	}
 }
+
+// syntheticRDF is a small, hand-built rhea.rdf fixture exercising three
+// ReactiveParts in the two shapes ParseRhea has to resolve: SmallMolecule
+// compounds (Compound_1/2, resolved directly via the reactivePartFields Schema), and a
+// GenericPolypeptide compound whose ReactivePart fields live on a separate
+// Description (Compound_3/Compound_3_generic/Compound_3_rp1, joined through
+// compoundMap/reactivePartMap).
+const syntheticRDF = `<?xml version="1.0"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns:rh="http://rdf.rhea-db.org/">
+  <rdf:Description rdf:about="http://rdf.rhea-db.org/10000">
+    <rdf:subClassOf rdf:resource="http://rdf.rhea-db.org/DirectionalReaction"/>
+    <rh:id>10000</rh:id>
+    <rh:accession>RHEA:10000</rh:accession>
+    <rh:equation>a = b</rh:equation>
+    <rh:isChemicallyBalanced>true</rh:isChemicallyBalanced>
+    <rh:isTransport>false</rh:isTransport>
+    <rh:substrates rdf:resource="http://rdf.rhea-db.org/10000_L"/>
+    <rh:products rdf:resource="http://rdf.rhea-db.org/10000_R"/>
+  </rdf:Description>
+  <rdf:Description rdf:about="http://rdf.rhea-db.org/10000_L">
+    <rh:contains1 rdf:resource="http://rdf.rhea-db.org/Compound_1"/>
+  </rdf:Description>
+  <rdf:Description rdf:about="http://rdf.rhea-db.org/10000_R">
+    <rh:contains1 rdf:resource="http://rdf.rhea-db.org/Compound_2"/>
+  </rdf:Description>
+  <rdf:Description rdf:about="http://rdf.rhea-db.org/Compound_1">
+    <rh:compound rdf:resource="http://rdf.rhea-db.org/Compound_1_ChemComp"/>
+  </rdf:Description>
+  <rdf:Description rdf:about="http://rdf.rhea-db.org/Compound_1_ChemComp">
+    <rdf:subClassOf rdf:resource="http://rdf.rhea-db.org/SmallMolecule"/>
+    <rh:id>1</rh:id>
+    <rh:accession>CHEBI:111</rh:accession>
+    <rh:name>Foo</rh:name>
+    <rh:chebi rdf:resource="http://purl.obolibrary.org/obo/CHEBI_111"/>
+  </rdf:Description>
+  <rdf:Description rdf:about="http://rdf.rhea-db.org/Compound_2">
+    <rh:compound rdf:resource="http://rdf.rhea-db.org/Compound_2_ChemComp"/>
+  </rdf:Description>
+  <rdf:Description rdf:about="http://rdf.rhea-db.org/Compound_2_ChemComp">
+    <rdf:subClassOf rdf:resource="http://rdf.rhea-db.org/SmallMolecule"/>
+    <rh:id>2</rh:id>
+    <rh:accession>CHEBI:222</rh:accession>
+    <rh:name>Bar</rh:name>
+    <rh:chebi rdf:resource="http://purl.obolibrary.org/obo/CHEBI_222"/>
+  </rdf:Description>
+  <rdf:Description rdf:about="http://rdf.rhea-db.org/Compound_3_generic">
+    <rdf:subClassOf rdf:resource="http://rdf.rhea-db.org/GenericPolypeptide"/>
+    <rh:id>3</rh:id>
+    <rh:accession>GENERIC:3</rh:accession>
+    <rh:name>Baz</rh:name>
+    <rh:reactivePart rdf:resource="http://rdf.rhea-db.org/Compound_3_rp1"/>
+  </rdf:Description>
+  <rdf:Description rdf:about="http://rdf.rhea-db.org/Compound_3_rp1">
+    <rdf:subClassOf rdf:resource="http://rdf.rhea-db.org/ReactivePart"/>
+    <rh:id>31</rh:id>
+    <rh:accession>RP:31</rh:accession>
+    <rh:position>1</rh:position>
+  </rdf:Description>
+</rdf:RDF>
+`
+
+func TestParseRheaSynthetic(t *testing.T) {
+	rhea, err := ParseRhea([]byte(syntheticRDF))
+	if err != nil {
+		t.Fatalf("ParseRhea returned error: %v", err)
+	}
+
+	if len(rhea.Reactions) != 1 {
+		t.Fatalf("expected 1 reaction, got %d: %+v", len(rhea.Reactions), rhea.Reactions)
+	}
+	reaction := rhea.Reactions[0]
+	if reaction.Id != 10000 || reaction.Accession != "RHEA:10000" || !reaction.Directional {
+		t.Fatalf("unexpected reaction: %+v", reaction)
+	}
+	if !reaction.IsChemicallyBalanced || reaction.IsTransport {
+		t.Fatalf("unexpected reaction flags: %+v", reaction)
+	}
+
+	if len(rhea.ReactionParticipants) != 2 {
+		t.Fatalf("expected 2 reaction participants, got %d: %+v", len(rhea.ReactionParticipants), rhea.ReactionParticipants)
+	}
+
+	if len(rhea.ReactiveParts) != 3 {
+		t.Fatalf("expected 3 reactive parts, got %d: %+v", len(rhea.ReactiveParts), rhea.ReactiveParts)
+	}
+	var foundGeneric bool
+	for _, part := range rhea.ReactiveParts {
+		switch part.Name {
+		case "Foo":
+			if part.Chebi != "http://purl.obolibrary.org/obo/CHEBI_111" {
+				t.Errorf("unexpected chebi for Foo: %+v", part)
+			}
+		case "Bar":
+			if part.Chebi != "http://purl.obolibrary.org/obo/CHEBI_222" {
+				t.Errorf("unexpected chebi for Bar: %+v", part)
+			}
+		}
+		if part.CompoundName == "Baz" {
+			foundGeneric = true
+			if part.CompoundReactionParticipantLink != "http://rdf.rhea-db.org/Compound_3_rp1" || part.Position != "1" {
+				t.Errorf("GenericPolypeptide reactive part not joined via reactivePartMap: %+v", part)
+			}
+		}
+	}
+	if !foundGeneric {
+		t.Fatalf("expected to find the GenericPolypeptide-joined reactive part, got %+v", rhea.ReactiveParts)
+	}
+}
@@ -0,0 +1,195 @@
+// Package rdf is a small, general-purpose RDF/XML reader. It knows nothing
+// about Rhea: it reads <rdf:Description> elements into subject/predicate/
+// object Triples and lets callers walk those triples by predicate, the way a
+// SPARQL-like query would. rhea.ParseRhea is built on it, describing the
+// Rhea vocabulary (rdf.rhea-db.org URIs) with Schemas; rhea.StreamRhea does
+// not use it and decodes Rhea's XML shape directly. A caller can point this
+// engine at any RDF/XML dump that follows the same "one Description per subject, one
+// child element per predicate" shape - Rhea, ChEBI, GO, or anything else -
+// and describe with a Schema instead of a hand-written switch.
+package rdf
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// Triple is a single subject/predicate/object statement, following the same
+// names used in RDF literature. Subject is always an IRI (the rdf:about of
+// the enclosing Description). Predicate is the child element's local tag
+// name (e.g. "equation", "subClassOf"), matching how rhea's Description
+// struct tags ignore namespace prefixes too. Object is the element's
+// rdf:resource attribute when present, and its character content otherwise.
+type Triple struct {
+	Subject   string
+	Predicate string
+	Object    string
+}
+
+// Graph is a set of Triples with indexes that make predicate walks cheap.
+type Graph struct {
+	Triples []Triple
+
+	bySubject   map[string][]int
+	byPredicate map[string][]int
+}
+
+// Parse reads an RDF/XML document from r and returns the Graph of Triples it
+// describes. Every child element of every <rdf:Description about="...">
+// becomes one Triple, keyed by the Description's about attribute.
+func Parse(r io.Reader) (*Graph, error) {
+	decoder := xml.NewDecoder(r)
+	var triples []Triple
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		se, ok := token.(xml.StartElement)
+		if !ok || se.Name.Local != "Description" {
+			continue
+		}
+
+		var subject string
+		for _, attr := range se.Attr {
+			if attr.Name.Local == "about" {
+				subject = attr.Value
+			}
+		}
+
+		childTriples, err := parseDescriptionChildren(decoder, subject)
+		if err != nil {
+			return nil, err
+		}
+		triples = append(triples, childTriples...)
+	}
+
+	return NewGraph(triples), nil
+}
+
+// parseDescriptionChildren consumes tokens up to the matching EndElement for
+// the Description that was just opened, emitting one Triple per child.
+func parseDescriptionChildren(decoder *xml.Decoder, subject string) ([]Triple, error) {
+	var triples []Triple
+	depth := 0
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			predicate := t.Name.Local
+			var object string
+			for _, attr := range t.Attr {
+				if attr.Name.Local == "resource" {
+					object = attr.Value
+				}
+			}
+			if object == "" {
+				if err := decoder.DecodeElement(&object, &t); err != nil {
+					return nil, err
+				}
+			} else if err := decoder.Skip(); err != nil {
+				return nil, err
+			}
+			depth--
+			triples = append(triples, Triple{Subject: subject, Predicate: predicate, Object: object})
+		case xml.EndElement:
+			if depth == 0 {
+				return triples, nil
+			}
+			depth--
+		}
+	}
+}
+
+// NewGraph builds a Graph (and its subject/predicate indexes) from a flat
+// slice of Triples, such as one assembled by hand or filtered from another
+// Graph.
+func NewGraph(triples []Triple) *Graph {
+	g := &Graph{
+		Triples:     triples,
+		bySubject:   make(map[string][]int),
+		byPredicate: make(map[string][]int),
+	}
+	for i, t := range triples {
+		g.bySubject[t.Subject] = append(g.bySubject[t.Subject], i)
+		g.byPredicate[t.Predicate] = append(g.byPredicate[t.Predicate], i)
+	}
+	return g
+}
+
+// Subjects returns every distinct Subject in g, in the order each first
+// appears in g.Triples (which, for a Graph built by Parse, is the document
+// order of the Descriptions they came from).
+func (g *Graph) Subjects() []string {
+	seen := make(map[string]bool, len(g.bySubject))
+	subjects := make([]string, 0, len(g.bySubject))
+	for _, t := range g.Triples {
+		if seen[t.Subject] {
+			continue
+		}
+		seen[t.Subject] = true
+		subjects = append(subjects, t.Subject)
+	}
+	return subjects
+}
+
+// ForSubject returns every Triple whose Subject is subject.
+func (g *Graph) ForSubject(subject string) []Triple {
+	var out []Triple
+	for _, i := range g.bySubject[subject] {
+		out = append(out, g.Triples[i])
+	}
+	return out
+}
+
+// ForPredicate returns every Triple whose Predicate is predicate.
+func (g *Graph) ForPredicate(predicate string) []Triple {
+	var out []Triple
+	for _, i := range g.byPredicate[predicate] {
+		out = append(out, g.Triples[i])
+	}
+	return out
+}
+
+// Walk returns the Objects of every Triple matching (subject, predicate) -
+// one hop along predicate from subject.
+func (g *Graph) Walk(subject, predicate string) []string {
+	var out []string
+	for _, i := range g.bySubject[subject] {
+		if t := g.Triples[i]; t.Predicate == predicate {
+			out = append(out, t.Object)
+		}
+	}
+	return out
+}
+
+// WalkStar follows predicate from subject transitively, as in a SPARQL
+// "predicate*" path, and returns every subject reachable that way. It is
+// meant for walks like rh:contains* from a reaction side down to the
+// reactive parts nested underneath it. Cycles are not revisited.
+func (g *Graph) WalkStar(subject, predicate string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	var visit func(string)
+	visit = func(s string) {
+		for _, next := range g.Walk(s, predicate) {
+			if seen[next] {
+				continue
+			}
+			seen[next] = true
+			out = append(out, next)
+			visit(next)
+		}
+	}
+	visit(subject)
+	return out
+}
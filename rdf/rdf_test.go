@@ -0,0 +1,82 @@
+package rdf
+
+import (
+	"strings"
+	"testing"
+)
+
+const testRDF = `<?xml version="1.0"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns:rh="http://rdf.rhea-db.org/">
+  <rdf:Description rdf:about="http://rdf.rhea-db.org/10000">
+    <rdf:subClassOf rdf:resource="http://rdf.rhea-db.org/DirectionalReaction"/>
+    <rh:accession>RHEA:10000</rh:accession>
+    <rh:substrates rdf:resource="http://rdf.rhea-db.org/10000_L"/>
+    <rh:products rdf:resource="http://rdf.rhea-db.org/10000_R"/>
+  </rdf:Description>
+  <rdf:Description rdf:about="http://rdf.rhea-db.org/10000_L">
+    <rh:contains1 rdf:resource="http://rdf.rhea-db.org/Compound_1"/>
+  </rdf:Description>
+</rdf:RDF>
+`
+
+func TestParseAndWalk(t *testing.T) {
+	graph, err := Parse(strings.NewReader(testRDF))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	accessions := graph.Walk("http://rdf.rhea-db.org/10000", "accession")
+	if len(accessions) != 1 || accessions[0] != "RHEA:10000" {
+		t.Fatalf("expected one accession triple, got %v", accessions)
+	}
+
+	substrates := graph.Walk("http://rdf.rhea-db.org/10000", "substrates")
+	if len(substrates) != 1 || substrates[0] != "http://rdf.rhea-db.org/10000_L" {
+		t.Fatalf("expected one substrates triple, got %v", substrates)
+	}
+
+	contains := graph.Walk("http://rdf.rhea-db.org/10000_L", "contains1")
+	if len(contains) != 1 || contains[0] != "http://rdf.rhea-db.org/Compound_1" {
+		t.Fatalf("expected one contains1 triple, got %v", contains)
+	}
+}
+
+func TestSubjectsPreservesDocumentOrder(t *testing.T) {
+	graph, err := Parse(strings.NewReader(testRDF))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	subjects := graph.Subjects()
+	want := []string{"http://rdf.rhea-db.org/10000", "http://rdf.rhea-db.org/10000_L"}
+	if len(subjects) != len(want) {
+		t.Fatalf("expected %d subjects, got %v", len(want), subjects)
+	}
+	for i := range want {
+		if subjects[i] != want[i] {
+			t.Fatalf("subjects[%d] = %q, want %q", i, subjects[i], want[i])
+		}
+	}
+}
+
+func TestProject(t *testing.T) {
+	graph, err := Parse(strings.NewReader(testRDF))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	records := graph.Project(Schema{
+		TypeURI: "http://rdf.rhea-db.org/DirectionalReaction",
+		Fields:  []string{"accession", "substrates", "products"},
+	})
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(records))
+	}
+	record := records[0]
+	if record.Subject != "http://rdf.rhea-db.org/10000" {
+		t.Errorf("unexpected subject: %q", record.Subject)
+	}
+	if got := record.Values["accession"]; len(got) != 1 || got[0] != "RHEA:10000" {
+		t.Errorf("unexpected accession values: %v", got)
+	}
+}
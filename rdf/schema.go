@@ -0,0 +1,53 @@
+package rdf
+
+// Schema declares how one RDF "kind" of subject should be read out of a
+// Graph, in place of a hand-written switch over hardcoded URIs. TypeURI is the subClassOf object that marks a subject as
+// being of this kind (e.g. "http://rdf.rhea-db.org/DirectionalReaction").
+// Fields lists the predicates that kind of subject is expected to carry.
+type Schema struct {
+	TypeURI string
+	Fields  []string
+}
+
+// Record is one subject projected through a Schema: Subject is the IRI that
+// matched TypeURI, and Values holds, for each of Schema.Fields, every Object
+// found for that predicate on Subject (in Triple order, so repeated
+// predicates like rh:substrates are preserved in full).
+type Record struct {
+	Subject string
+	Values  map[string][]string
+}
+
+// Project walks g for every subject with a SubclassPredicate triple whose
+// object is schema.TypeURI, and returns one Record per match with
+// schema.Fields resolved via Graph.Walk.
+//
+// ParseRhea reads Rhea's reactions and reactive parts through Project. A
+// caller who wants Rhea's DirectionalReaction shape writes
+//
+//	rdf.Schema{
+//	    TypeURI: "http://rdf.rhea-db.org/DirectionalReaction",
+//	    Fields:  []string{"accession", "equation", "ec"},
+//	}
+//
+// instead of editing ParseRhea itself, and the same Graph can be projected
+// through as many Schemas as there are kinds of subject in the dump.
+func (g *Graph) Project(schema Schema) []Record {
+	var records []Record
+	for _, t := range g.ForPredicate(SubclassPredicate) {
+		if t.Object != schema.TypeURI {
+			continue
+		}
+		record := Record{Subject: t.Subject, Values: make(map[string][]string, len(schema.Fields))}
+		for _, field := range schema.Fields {
+			record.Values[field] = g.Walk(t.Subject, field)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// SubclassPredicate is the predicate name Parse assigns to an RDF/XML
+// "<rdf:subClassOf rdf:resource=".../>" element, and the predicate Project
+// uses to find which Schema a subject belongs to.
+const SubclassPredicate = "subClassOf"
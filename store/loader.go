@@ -0,0 +1,216 @@
+package store
+
+import (
+	"database/sql"
+
+	"github.com/Koeng101/rhea"
+	"github.com/lib/pq"
+)
+
+// insertBatchSize is how many rows loadWithBatchedInserts inserts per
+// transaction (with one prepared statement each) on non-Postgres drivers.
+const insertBatchSize = 500
+
+// LoadRhea bulk loads r into the schema created by PostgresSchema or
+// SQLiteSchema. Postgres connections (detected by driver type) load with
+// pq.CopyIn for COPY-speed throughput; every other driver, including
+// SQLite, falls back to batched, prepared INSERT statements.
+func LoadRhea(db *sql.DB, r rhea.Rhea) error {
+	if _, ok := db.Driver().(*pq.Driver); ok {
+		return loadWithCopy(db, r)
+	}
+	return loadWithBatchedInserts(db, r)
+}
+
+func loadWithCopy(db *sql.DB, r rhea.Rhea) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := copyReactions(tx, r.Reactions); err != nil {
+		return err
+	}
+	if err := copyReactiveParts(tx, r.ReactiveParts); err != nil {
+		return err
+	}
+	if err := copyReactionParticipants(tx, r.ReactionParticipants); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func copyReactions(tx *sql.Tx, reactions []rhea.Reaction) error {
+	stmt, err := tx.Prepare(pq.CopyIn("reactions",
+		"id", "directional", "accession", "status", "comment", "equation",
+		"htmlequation", "ischemicallybalanced", "istransport", "ec", "location"))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, reaction := range reactions {
+		if _, err := stmt.Exec(
+			reaction.Id, reaction.Directional, reaction.Accession, reaction.Status,
+			reaction.Comment, reaction.Equation, reaction.HtmlEquation,
+			reaction.IsChemicallyBalanced, reaction.IsTransport, reaction.Ec, reaction.Location,
+		); err != nil {
+			return err
+		}
+	}
+	_, err = stmt.Exec()
+	return err
+}
+
+func copyReactiveParts(tx *sql.Tx, parts []rhea.ReactivePart) error {
+	stmt, err := tx.Prepare(pq.CopyIn("reactive_parts",
+		"id", "accession", "position", "name", "htmlname", "formula", "charge", "chebi",
+		"polymerizationindex", "reactionparticipantlink", "compoundid", "compoundaccession",
+		"compoundname", "compoundhtmlname", "compoundtype", "inchi", "inchikey", "smiles"))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, part := range parts {
+		if _, err := stmt.Exec(
+			part.Id, part.Accession, part.Position, part.Name, part.HtmlName, part.Formula,
+			part.Charge, part.Chebi, part.PolymerizationIndex, part.CompoundReactionParticipantLink,
+			part.CompoundId, part.CompoundAccession, part.CompoundName, part.CompoundHtmlName,
+			part.CompoundType, part.InChI, part.InChIKey, part.SMILES,
+		); err != nil {
+			return err
+		}
+	}
+	_, err = stmt.Exec()
+	return err
+}
+
+func copyReactionParticipants(tx *sql.Tx, participants []rhea.ReactionParticipant) error {
+	stmt, err := tx.Prepare(pq.CopyIn("reaction_participants",
+		"reactionside", "contains", "containsn", "minus", "plus", "compound"))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, participant := range participants {
+		if _, err := stmt.Exec(
+			participant.ReactionSide, participant.Contains, participant.ContainsN,
+			participant.Minus, participant.Plus, participant.Compound,
+		); err != nil {
+			return err
+		}
+	}
+	_, err = stmt.Exec()
+	return err
+}
+
+// loadWithBatchedInserts loads r in chunks of insertBatchSize rows per
+// table, each chunk committed as its own transaction, so that a SQLite (or
+// other non-Postgres) load of the full Rhea dump doesn't hold one
+// unboundedly large transaction open.
+func loadWithBatchedInserts(db *sql.DB, r rhea.Rhea) error {
+	if err := batchedInsert(db, len(r.Reactions), insertReactionsBatch(r.Reactions)); err != nil {
+		return err
+	}
+	if err := batchedInsert(db, len(r.ReactiveParts), insertReactivePartsBatch(r.ReactiveParts)); err != nil {
+		return err
+	}
+	return batchedInsert(db, len(r.ReactionParticipants), insertReactionParticipantsBatch(r.ReactionParticipants))
+}
+
+// batchedInsert runs insert(tx, start, end) once per insertBatchSize-sized
+// chunk of [0, rowCount), each inside its own transaction.
+func batchedInsert(db *sql.DB, rowCount int, insert func(tx *sql.Tx, start, end int) error) error {
+	for start := 0; start < rowCount; start += insertBatchSize {
+		end := start + insertBatchSize
+		if end > rowCount {
+			end = rowCount
+		}
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		if err := insert(tx, start, end); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func insertReactionsBatch(reactions []rhea.Reaction) func(tx *sql.Tx, start, end int) error {
+	return func(tx *sql.Tx, start, end int) error {
+		stmt, err := tx.Prepare(`INSERT INTO reactions
+			(id, directional, accession, status, comment, equation, htmlequation, ischemicallybalanced, istransport, ec, location)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		for _, reaction := range reactions[start:end] {
+			if _, err := stmt.Exec(
+				reaction.Id, reaction.Directional, reaction.Accession, reaction.Status,
+				reaction.Comment, reaction.Equation, reaction.HtmlEquation,
+				reaction.IsChemicallyBalanced, reaction.IsTransport, reaction.Ec, reaction.Location,
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func insertReactivePartsBatch(parts []rhea.ReactivePart) func(tx *sql.Tx, start, end int) error {
+	return func(tx *sql.Tx, start, end int) error {
+		stmt, err := tx.Prepare(`INSERT INTO reactive_parts
+			(id, accession, position, name, htmlname, formula, charge, chebi, polymerizationindex,
+			 reactionparticipantlink, compoundid, compoundaccession, compoundname, compoundhtmlname,
+			 compoundtype, inchi, inchikey, smiles)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		for _, part := range parts[start:end] {
+			if _, err := stmt.Exec(
+				part.Id, part.Accession, part.Position, part.Name, part.HtmlName, part.Formula,
+				part.Charge, part.Chebi, part.PolymerizationIndex, part.CompoundReactionParticipantLink,
+				part.CompoundId, part.CompoundAccession, part.CompoundName, part.CompoundHtmlName,
+				part.CompoundType, part.InChI, part.InChIKey, part.SMILES,
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func insertReactionParticipantsBatch(participants []rhea.ReactionParticipant) func(tx *sql.Tx, start, end int) error {
+	return func(tx *sql.Tx, start, end int) error {
+		stmt, err := tx.Prepare(`INSERT INTO reaction_participants
+			(reactionside, contains, containsn, minus, plus, compound)
+			VALUES (?, ?, ?, ?, ?, ?)`)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		for _, participant := range participants[start:end] {
+			if _, err := stmt.Exec(
+				participant.ReactionSide, participant.Contains, participant.ContainsN,
+				participant.Minus, participant.Plus, participant.Compound,
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
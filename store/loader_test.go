@@ -0,0 +1,89 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/Koeng101/rhea"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func newSQLiteDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open sqlite3 db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if _, err := db.Exec(SQLiteSchema); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	return db
+}
+
+// testRheaFixture builds a Rhea value with rowCount independent
+// reactions/reactiveParts/participants, each linked by its own unique
+// compound IRI so foreign key and uniqueness constraints hold regardless of
+// rowCount.
+func testRheaFixture(rowCount int) rhea.Rhea {
+	var r rhea.Rhea
+	for i := 0; i < rowCount; i++ {
+		id := i + 1
+		link := fmt.Sprintf("http://rdf.rhea-db.org/Compound_%d", id)
+		r.Reactions = append(r.Reactions, rhea.Reaction{Id: id, Accession: fmt.Sprintf("RHEA:%d", id)})
+		r.ReactiveParts = append(r.ReactiveParts, rhea.ReactivePart{Id: id, CompoundReactionParticipantLink: link})
+		r.ReactionParticipants = append(r.ReactionParticipants, rhea.ReactionParticipant{
+			ReactionSide: fmt.Sprintf("http://rdf.rhea-db.org/side_%d", id),
+			Contains:     1,
+			Compound:     link})
+	}
+	return r
+}
+
+func assertRowCount(t *testing.T, db *sql.DB, table string, want int) {
+	t.Helper()
+	var got int
+	if err := db.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&got); err != nil {
+		t.Fatalf("failed to count rows in %s: %v", table, err)
+	}
+	if got != want {
+		t.Errorf("expected %d rows in %s, got %d", want, table, got)
+	}
+}
+
+// TestLoadRheaBatchedInserts loads insertBatchSize+1 rows per table, forcing
+// loadWithBatchedInserts/batchedInsert to cross a chunk boundary (one full
+// batch committed, one partial batch committed after it) instead of only
+// ever exercising a single transaction.
+func TestLoadRheaBatchedInserts(t *testing.T) {
+	db := newSQLiteDB(t)
+	r := testRheaFixture(insertBatchSize + 1)
+
+	if err := LoadRhea(db, r); err != nil {
+		t.Fatalf("LoadRhea returned error: %v", err)
+	}
+
+	assertRowCount(t, db, "reactions", insertBatchSize+1)
+	assertRowCount(t, db, "reactive_parts", insertBatchSize+1)
+	assertRowCount(t, db, "reaction_participants", insertBatchSize+1)
+}
+
+// TestLoadRheaEnforcesForeignKey checks the reaction_participants.compound ->
+// reactive_parts.reactionparticipantlink foreign key declared in
+// SQLiteSchema actually holds once a caller has turned enforcement on.
+func TestLoadRheaEnforcesForeignKey(t *testing.T) {
+	db := newSQLiteDB(t)
+	if _, err := db.Exec("PRAGMA foreign_keys = ON"); err != nil {
+		t.Fatalf("failed to enable foreign keys: %v", err)
+	}
+
+	r := rhea.Rhea{
+		ReactionParticipants: []rhea.ReactionParticipant{
+			{ReactionSide: "http://rdf.rhea-db.org/side_1", Contains: 1, Compound: "http://rdf.rhea-db.org/does-not-exist"},
+		},
+	}
+	if err := LoadRhea(db, r); err == nil {
+		t.Fatalf("expected LoadRhea to fail inserting a reaction_participants row with no matching reactive_parts.reactionparticipantlink")
+	}
+}
@@ -0,0 +1,119 @@
+// Package store turns a parsed rhea.Rhea value into rows in a SQL database.
+// It emits the CREATE TABLE DDL implied by the `db` struct tags on
+// rhea.ReactivePart, rhea.ReactionParticipant, and rhea.Reaction, and loads a
+// Rhea value into those tables with LoadRhea.
+package store
+
+// PostgresSchema creates the normalized Rhea schema on Postgres. reactions
+// and reactive_parts are the two entity tables; reaction_participants is the
+// many-to-many linkage between a reaction side (identified by the IRI in
+// rhea.ReactionParticipant.ReactionSide) and the reactive parts that side
+// contains. chebi, ec, and accession are indexed since those are the
+// columns downstream federated queries typically join on.
+const PostgresSchema = `
+CREATE TABLE IF NOT EXISTS reactions (
+	id                   integer PRIMARY KEY,
+	directional          boolean NOT NULL,
+	accession            text NOT NULL UNIQUE,
+	status               text,
+	comment              text,
+	equation             text,
+	htmlequation         text,
+	ischemicallybalanced boolean,
+	istransport          boolean,
+	ec                   text,
+	location             text
+);
+CREATE INDEX IF NOT EXISTS idx_reactions_ec ON reactions (ec);
+CREATE INDEX IF NOT EXISTS idx_reactions_accession ON reactions (accession);
+
+CREATE TABLE IF NOT EXISTS reactive_parts (
+	id                      integer PRIMARY KEY,
+	accession               text,
+	position                text,
+	name                    text,
+	htmlname                text,
+	formula                 text,
+	charge                  text,
+	chebi                   text,
+	polymerizationindex     text,
+	reactionparticipantlink text NOT NULL UNIQUE,
+	compoundid              integer,
+	compoundaccession       text,
+	compoundname            text,
+	compoundhtmlname        text,
+	compoundtype            text,
+	inchi                   text,
+	inchikey                text,
+	smiles                  text
+);
+CREATE INDEX IF NOT EXISTS idx_reactive_parts_chebi ON reactive_parts (chebi);
+CREATE INDEX IF NOT EXISTS idx_reactive_parts_accession ON reactive_parts (accession);
+
+CREATE TABLE IF NOT EXISTS reaction_participants (
+	reactionside text NOT NULL,
+	contains     integer NOT NULL,
+	containsn    boolean NOT NULL,
+	minus        boolean NOT NULL,
+	plus         boolean NOT NULL,
+	compound     text NOT NULL REFERENCES reactive_parts (reactionparticipantlink)
+);
+CREATE INDEX IF NOT EXISTS idx_reaction_participants_reactionside ON reaction_participants (reactionside);
+CREATE INDEX IF NOT EXISTS idx_reaction_participants_compound ON reaction_participants (compound);
+`
+
+// SQLiteSchema creates the same schema on SQLite. SQLite has no native
+// boolean type, so the boolean columns above become 0/1 integers; foreign
+// keys are declared but SQLite only enforces them when the caller has run
+// "PRAGMA foreign_keys = ON".
+const SQLiteSchema = `
+CREATE TABLE IF NOT EXISTS reactions (
+	id                   integer PRIMARY KEY,
+	directional          integer NOT NULL,
+	accession            text NOT NULL UNIQUE,
+	status               text,
+	comment              text,
+	equation             text,
+	htmlequation         text,
+	ischemicallybalanced integer,
+	istransport          integer,
+	ec                   text,
+	location             text
+);
+CREATE INDEX IF NOT EXISTS idx_reactions_ec ON reactions (ec);
+CREATE INDEX IF NOT EXISTS idx_reactions_accession ON reactions (accession);
+
+CREATE TABLE IF NOT EXISTS reactive_parts (
+	id                      integer PRIMARY KEY,
+	accession               text,
+	position                text,
+	name                    text,
+	htmlname                text,
+	formula                 text,
+	charge                  text,
+	chebi                   text,
+	polymerizationindex     text,
+	reactionparticipantlink text NOT NULL UNIQUE,
+	compoundid              integer,
+	compoundaccession       text,
+	compoundname            text,
+	compoundhtmlname        text,
+	compoundtype            text,
+	inchi                   text,
+	inchikey                text,
+	smiles                  text
+);
+CREATE INDEX IF NOT EXISTS idx_reactive_parts_chebi ON reactive_parts (chebi);
+CREATE INDEX IF NOT EXISTS idx_reactive_parts_accession ON reactive_parts (accession);
+
+CREATE TABLE IF NOT EXISTS reaction_participants (
+	reactionside text NOT NULL,
+	contains     integer NOT NULL,
+	containsn    integer NOT NULL,
+	minus        integer NOT NULL,
+	plus         integer NOT NULL,
+	compound     text NOT NULL REFERENCES reactive_parts (reactionparticipantlink)
+);
+CREATE INDEX IF NOT EXISTS idx_reaction_participants_reactionside ON reaction_participants (reactionside);
+CREATE INDEX IF NOT EXISTS idx_reaction_participants_compound ON reaction_participants (compound);
+`
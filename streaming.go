@@ -0,0 +1,238 @@
+package rhea
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+/******************************************************************************
+
+Streaming parser
+
+ParseRhea reads an entire rhea.rdf dump into memory before it can return
+anything, which is expensive for a database that ships as a multi-hundred-MB
+RDF/XML file. StreamRhea walks the same <rdf:Description> elements but does
+so one at a time with encoding/xml's Decoder.Token API, and emits a RheaEvent
+per Reaction, ReactivePart, or ReactionParticipant it discovers instead of
+building a single Rhea struct.
+
+ParseRhea's resolution of Compound -> ReactivePart and ReactionSide ->
+Compound (compoundMap) requires having seen every Description in the dump
+before some ReactiveParts and ReactionParticipants can be resolved, because
+Rhea references these by IRI and the referenced Description can appear
+either before or after the reference in document order. StreamRhea therefore
+runs in two passes over r: a first pass that only rebuilds compoundMap and
+reactivePartMap (no handler calls, and no Rhea-shaped values are kept in
+memory beyond these two maps), and a second pass that re-decodes the document
+and calls handler once per resolved event. Because of this, r must be an
+io.ReadSeeker: StreamRhea seeks back to the start of r between passes.
+
+******************************************************************************/
+
+type RheaEventType int
+
+const (
+	ReactionEvent RheaEventType = iota
+	ReactivePartEvent
+	ReactionParticipantEvent
+)
+
+// RheaEvent is emitted once per Reaction, ReactivePart, or
+// ReactionParticipant found while streaming a rhea.rdf dump. Only the field
+// matching Type is populated.
+type RheaEvent struct {
+	Type                RheaEventType
+	Reaction            Reaction
+	ReactivePart        ReactivePart
+	ReactionParticipant ReactionParticipant
+}
+
+// StreamRhea parses a rhea.rdf dump from r, calling handler once for every
+// Reaction, ReactivePart, and ReactionParticipant it finds. Unlike ParseRhea,
+// it never holds the full set of parsed results in memory at once - only the
+// compoundMap and reactivePartMap built during its first pass over r. If
+// handler returns an error, StreamRhea stops and returns that error.
+func StreamRhea(r io.ReadSeeker, handler func(RheaEvent) error) error {
+	compoundMap, reactivePartMap, err := buildRheaMaps(r)
+	if err != nil {
+		return err
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		se, ok := token.(xml.StartElement)
+		if !ok || se.Name.Local != "Description" {
+			continue
+		}
+		var description Description
+		if err := decoder.DecodeElement(&description, &se); err != nil {
+			return err
+		}
+		if err := emitDescription(description, compoundMap, reactivePartMap, handler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buildRheaMaps makes the first, map-only pass over r described in
+// StreamRhea's doc comment. It builds the compoundMap and reactivePartMap
+// that ParseRhea also builds, in document order, and discards everything
+// else.
+func buildRheaMaps(r io.ReadSeeker) (map[string]string, map[string]ReactivePart, error) {
+	compoundMap := make(map[string]string)
+	reactivePartMap := make(map[string]ReactivePart)
+
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		se, ok := token.(xml.StartElement)
+		if !ok || se.Name.Local != "Description" {
+			continue
+		}
+		var description Description
+		if err := decoder.DecodeElement(&description, &se); err != nil {
+			return nil, nil, err
+		}
+
+		if (len(description.Subclass) == 0) && (description.ReactivePartXml.Resource != "") {
+			compoundMap[description.ReactivePartXml.Resource] = description.About
+		}
+		if description.Compound.Resource != "" {
+			compoundMap[description.About] = description.Compound.Resource
+		}
+
+		for _, subclass := range description.Subclass {
+			if !isGenericCompoundType(subclass.Resource) {
+				continue
+			}
+			compoundType := subclass.Resource[len(rheaNS):]
+			reactivePartMap[description.About] = ReactivePart{
+				CompoundId:        description.Id,
+				CompoundAccession: description.Accession,
+				CompoundName:      description.Name,
+				CompoundHtmlName:  description.HtmlName,
+				CompoundType:      compoundType}
+			compoundMap[description.ReactivePartXml.Resource] = description.About
+		}
+	}
+	return compoundMap, reactivePartMap, nil
+}
+
+// emitDescription turns one Description into the Reactions, ReactiveParts,
+// and ReactionParticipants ParseRhea would build from it, calling handler
+// with a RheaEvent in place of appending to a Rhea struct.
+func emitDescription(description Description, compoundMap map[string]string, reactivePartMap map[string]ReactivePart, handler func(RheaEvent) error) error {
+	for _, subclass := range description.Subclass {
+		if directional, ok := reactionDirectional(subclass.Resource); ok {
+			event := RheaEvent{Type: ReactionEvent, Reaction: Reaction{
+				Id:                   description.Id,
+				Directional:          directional,
+				Accession:            description.Accession,
+				Status:               description.Status.Resource,
+				Comment:              description.Comment,
+				Equation:             description.Equation,
+				HtmlEquation:         description.HtmlEquation,
+				IsChemicallyBalanced: description.IsChemicallyBalanced,
+				IsTransport:          description.IsTransport,
+				Ec:                   description.EC.Resource,
+				Citations:            description.CitationStrings(),
+				Substrates:           description.SubstrateStrings(),
+				Products:             description.ProductStrings(),
+				SubstrateOrProducts:  description.SubstrateOrProductStrings(),
+				Location:             description.Location.Resource}}
+			if err := handler(event); err != nil {
+				return err
+			}
+			continue
+		}
+		if isSmallMoleculeType(subclass.Resource) {
+			compoundType := subclass.Resource[len(rheaNS):]
+			newReactivePart := ReactivePart{
+				Id:        description.Id,
+				Accession: description.Accession,
+				Position:  description.Position,
+				Name:      description.Name,
+				HtmlName:  description.HtmlName,
+				Formula:   description.Formula,
+				Charge:    description.Charge,
+				Chebi:     description.Chebi.Resource,
+
+				CompoundReactionParticipantLink: description.About,
+				CompoundId:                      description.Id,
+				CompoundAccession:               description.Accession,
+				CompoundName:                    description.Name,
+				CompoundHtmlName:                description.HtmlName,
+				CompoundType:                    compoundType}
+			if compoundType == "Polymer" {
+				newReactivePart.Chebi = description.UnderlyingChebi.Resource
+			}
+			var subclasses []string
+			for _, sc := range description.Subclass {
+				subclasses = append(subclasses, sc.Resource)
+			}
+			newReactivePart.SubclassOfChebi = subclassOfChebi(subclasses)
+			if err := handler(RheaEvent{Type: ReactivePartEvent, ReactivePart: newReactivePart}); err != nil {
+				return err
+			}
+			continue
+		}
+		if subclass.Resource == reactivePartTypeURI {
+			newReactivePart, ok := reactivePartMap[compoundMap[description.About]]
+			if !ok {
+				return &streamResolveError{about: description.About}
+			}
+			newReactivePart.CompoundReactionParticipantLink = description.About
+			newReactivePart.Id = description.Id
+			newReactivePart.Accession = description.Accession
+			newReactivePart.Position = description.Position
+			newReactivePart.Name = description.Name
+			newReactivePart.HtmlName = description.HtmlName
+			newReactivePart.Formula = description.Formula
+			newReactivePart.Charge = description.Charge
+			newReactivePart.Chebi = description.Chebi.Resource
+			if err := handler(RheaEvent{Type: ReactivePartEvent, ReactivePart: newReactivePart}); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, containsx := range description.ContainsX {
+		if !strings.Contains(containsx.XMLName.Local, "contains") {
+			continue
+		}
+		newReactionParticipant, err := participantFromContains(description.About, containsx.XMLName.Local, containsx.Content, compoundMap)
+		if err != nil {
+			return err
+		}
+		if err := handler(RheaEvent{Type: ReactionParticipantEvent, ReactionParticipant: newReactionParticipant}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type streamResolveError struct {
+	about string
+}
+
+func (e *streamResolveError) Error() string {
+	return "could not find " + e.about
+}
@@ -0,0 +1,125 @@
+package rhea
+
+import (
+	"bytes"
+	"testing"
+)
+
+// streamingTestRDF exercises the two-pass resolution StreamRhea exists for:
+// a GenericPolypeptide compound (Compound_3_generic) whose ReactivePart
+// fields live on a separate Description (Compound_3_rp1), only joinable via
+// the reactivePartMap/compoundMap built in buildRheaMaps's first pass. It
+// also covers the containsN/contains2n ReactionParticipant variants
+// alongside a plain numbered contains1.
+const streamingTestRDF = `<?xml version="1.0"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns:rh="http://rdf.rhea-db.org/">
+  <rdf:Description rdf:about="http://rdf.rhea-db.org/10000">
+    <rdf:subClassOf rdf:resource="http://rdf.rhea-db.org/DirectionalReaction"/>
+    <rh:id>10000</rh:id>
+    <rh:accession>RHEA:10000</rh:accession>
+    <rh:substrates rdf:resource="http://rdf.rhea-db.org/10000_L"/>
+    <rh:products rdf:resource="http://rdf.rhea-db.org/10000_R"/>
+  </rdf:Description>
+  <rdf:Description rdf:about="http://rdf.rhea-db.org/10000_L">
+    <rh:contains1 rdf:resource="http://rdf.rhea-db.org/Compound_1"/>
+    <rh:containsN rdf:resource="http://rdf.rhea-db.org/Compound_3"/>
+  </rdf:Description>
+  <rdf:Description rdf:about="http://rdf.rhea-db.org/10000_R">
+    <rh:contains2n rdf:resource="http://rdf.rhea-db.org/Compound_1"/>
+  </rdf:Description>
+  <rdf:Description rdf:about="http://rdf.rhea-db.org/Compound_1">
+    <rh:compound rdf:resource="http://rdf.rhea-db.org/Compound_1_ChemComp"/>
+  </rdf:Description>
+  <rdf:Description rdf:about="http://rdf.rhea-db.org/Compound_1_ChemComp">
+    <rdf:subClassOf rdf:resource="http://rdf.rhea-db.org/SmallMolecule"/>
+    <rh:id>1</rh:id>
+    <rh:accession>CHEBI:111</rh:accession>
+    <rh:name>Foo</rh:name>
+    <rh:chebi rdf:resource="http://purl.obolibrary.org/obo/CHEBI_111"/>
+  </rdf:Description>
+  <rdf:Description rdf:about="http://rdf.rhea-db.org/Compound_3">
+    <rh:reactivePart rdf:resource="http://rdf.rhea-db.org/Compound_3_rp1"/>
+  </rdf:Description>
+  <rdf:Description rdf:about="http://rdf.rhea-db.org/Compound_3_generic">
+    <rdf:subClassOf rdf:resource="http://rdf.rhea-db.org/GenericPolypeptide"/>
+    <rh:id>3</rh:id>
+    <rh:accession>GENERIC:3</rh:accession>
+    <rh:name>Baz</rh:name>
+    <rh:reactivePart rdf:resource="http://rdf.rhea-db.org/Compound_3_rp1"/>
+  </rdf:Description>
+  <rdf:Description rdf:about="http://rdf.rhea-db.org/Compound_3_rp1">
+    <rdf:subClassOf rdf:resource="http://rdf.rhea-db.org/ReactivePart"/>
+    <rh:id>31</rh:id>
+    <rh:accession>RP:31</rh:accession>
+    <rh:position>1</rh:position>
+  </rdf:Description>
+</rdf:RDF>
+`
+
+func TestStreamRhea(t *testing.T) {
+	var reactions []Reaction
+	var reactiveParts []ReactivePart
+	var participants []ReactionParticipant
+
+	handler := func(event RheaEvent) error {
+		switch event.Type {
+		case ReactionEvent:
+			reactions = append(reactions, event.Reaction)
+		case ReactivePartEvent:
+			reactiveParts = append(reactiveParts, event.ReactivePart)
+		case ReactionParticipantEvent:
+			participants = append(participants, event.ReactionParticipant)
+		}
+		return nil
+	}
+
+	if err := StreamRhea(bytes.NewReader([]byte(streamingTestRDF)), handler); err != nil {
+		t.Fatalf("StreamRhea returned error: %v", err)
+	}
+
+	if len(reactions) != 1 || reactions[0].Id != 10000 || !reactions[0].Directional {
+		t.Fatalf("unexpected reactions: %+v", reactions)
+	}
+
+	if len(reactiveParts) != 2 {
+		t.Fatalf("expected 2 reactive parts, got %d: %+v", len(reactiveParts), reactiveParts)
+	}
+	var foundGeneric bool
+	for _, part := range reactiveParts {
+		if part.Name == "Foo" && part.Chebi != "http://purl.obolibrary.org/obo/CHEBI_111" {
+			t.Errorf("unexpected chebi for Foo: %+v", part)
+		}
+		if part.CompoundName == "Baz" {
+			foundGeneric = true
+			if part.CompoundReactionParticipantLink != "http://rdf.rhea-db.org/Compound_3_rp1" || part.Position != "1" {
+				t.Errorf("GenericPolypeptide reactive part not joined via reactivePartMap/compoundMap: %+v", part)
+			}
+		}
+	}
+	if !foundGeneric {
+		t.Fatalf("expected to find the GenericPolypeptide-joined reactive part, got %+v", reactiveParts)
+	}
+
+	if len(participants) != 3 {
+		t.Fatalf("expected 3 reaction participants (contains1, containsN, contains2n), got %d: %+v", len(participants), participants)
+	}
+	byCompound := make(map[string]ReactionParticipant)
+	for _, p := range participants {
+		byCompound[p.Compound+"@"+p.ReactionSide] = p
+	}
+
+	plain := byCompound["http://rdf.rhea-db.org/Compound_1_ChemComp@http://rdf.rhea-db.org/10000_L"]
+	if plain.Contains != 1 || plain.ContainsN {
+		t.Errorf("unexpected contains1 participant: %+v", plain)
+	}
+
+	n := byCompound["@http://rdf.rhea-db.org/10000_L"]
+	if n.Contains != 1 || !n.ContainsN || n.Minus || n.Plus {
+		t.Errorf("unexpected containsN participant: %+v", n)
+	}
+
+	twoN := byCompound["http://rdf.rhea-db.org/Compound_1_ChemComp@http://rdf.rhea-db.org/10000_R"]
+	if twoN.Contains != 2 || !twoN.ContainsN || twoN.Minus || twoN.Plus {
+		t.Errorf("unexpected contains2n participant: %+v", twoN)
+	}
+}